@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Gkrumbach07/platform/components/backend/importer"
+	"github.com/Gkrumbach07/platform/components/backend/websocket"
+)
+
+const maxImportBodyBytes = 256 << 20 // 256 MiB
+
+// HandleImportSession is the inverse of HandleExportSession: it accepts an
+// exported session (or a recognized legacy/raw format) and rehydrates it
+// as agui-events.jsonl. With ?validate=true it reports what it would do
+// without writing anything.
+// POST /api/projects/:projectName/agentic-sessions/:sessionName/import
+func HandleImportSession(c *gin.Context) {
+	projectName := c.Param("projectName")
+	sessionName := c.Param("sessionName")
+	format := c.Query("format")
+	dryRun := c.Query("validate") == "true"
+	overwrite := c.Query("overwrite") == "true"
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxImportBodyBytes+1))
+	if err != nil {
+		log.Printf("Import: failed to read request body for %s/%s: %v", projectName, sessionName, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+	if len(body) > maxImportBodyBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Import payload too large"})
+		return
+	}
+
+	adapter, err := importer.Resolve(format, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := adapter.Import(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to parse import payload: %v", err)})
+		return
+	}
+
+	events, err = importer.RewriteSessionID(events, sessionName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to rewrite session id: %v", err)})
+		return
+	}
+
+	report, err := importer.Validate(events)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to validate import payload: %v", err)})
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{"format": adapter.Name(), "report": report})
+		return
+	}
+
+	sessionDir := fmt.Sprintf("%s/sessions/%s", websocket.StateBaseDir, sessionName)
+	aguiEventsPath := fmt.Sprintf("%s/agui-events.jsonl", sessionDir)
+
+	if !overwrite {
+		if info, err := os.Stat(aguiEventsPath); err == nil && info.Size() > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Session already has events; pass ?overwrite=true to replace them"})
+			return
+		}
+	}
+
+	if err := os.MkdirAll(sessionDir, 0o755); err != nil {
+		log.Printf("Import: failed to create session dir %s: %v", sessionDir, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session directory"})
+		return
+	}
+
+	if err := writeEventsAtomic(aguiEventsPath, events); err != nil {
+		log.Printf("Import: failed to write %s: %v", aguiEventsPath, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write session events"})
+		return
+	}
+
+	log.Printf("Import: wrote %d events to %s/%s from format %s", len(events), projectName, sessionName, adapter.Name())
+	c.JSON(http.StatusOK, gin.H{
+		"imported": len(events),
+		"format":   adapter.Name(),
+		"report":   report,
+	})
+}
+
+// writeEventsAtomic writes one event per line to path via a temp-file +
+// rename so readers never observe a partially written file.
+func writeEventsAtomic(path string, events [][]byte) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if _, err := f.Write(event); err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := f.Write([]byte("\n")); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -1,68 +1,119 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/Gkrumbach07/platform/components/backend/workflowcatalog"
+)
+
+const (
+	defaultDiscoveryCacheDir = "/tmp/ootb-discovery-cache"
+	defaultDiscoveryTTL      = 10 * time.Minute
+)
+
+var (
+	ootbCatalog        *workflowcatalog.Catalog
+	ootbCatalogOnce    sync.Once
+	ootbDiscoverer     *workflowcatalog.Discoverer
+	ootbDiscovererOnce sync.Once
 )
 
-type OOTBWorkflow struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	GitURL      string `json:"gitUrl"`
-	Branch      string `json:"branch"`
-	Path        string `json:"path,omitempty"`
-	Enabled     bool   `json:"enabled"`
+// getOOTBCatalog lazily loads the catalog on first use and starts watching
+// its backing file for changes so edits take effect without a pod restart.
+func getOOTBCatalog() *workflowcatalog.Catalog {
+	ootbCatalogOnce.Do(func() {
+		path := strings.TrimSpace(os.Getenv("OOTB_CATALOG_PATH"))
+		cat, err := workflowcatalog.Load(path)
+		if err != nil {
+			log.Printf("ListOOTBWorkflows: failed to load workflow catalog, falling back to defaults: %v", err)
+			cat, err = workflowcatalog.Load("")
+			if err != nil {
+				log.Fatalf("ListOOTBWorkflows: failed to load default workflow catalog: %v", err)
+			}
+		}
+		if err := cat.Watch(); err != nil {
+			log.Printf("ListOOTBWorkflows: failed to start catalog watcher: %v", err)
+		}
+		ootbCatalog = cat
+	})
+	return ootbCatalog
 }
 
-// ListOOTBWorkflows returns the list of out-of-the-box workflows
-// Configuration comes from environment variables with sensible defaults
+// getOOTBDiscoverer lazily creates the manifest discoverer and starts its
+// background refresher so clone/manifest results stay warm.
+func getOOTBDiscoverer() *workflowcatalog.Discoverer {
+	ootbDiscovererOnce.Do(func() {
+		cacheDir := strings.TrimSpace(os.Getenv("OOTB_DISCOVERY_CACHE_DIR"))
+		if cacheDir == "" {
+			cacheDir = defaultDiscoveryCacheDir
+		}
+		ttl := defaultDiscoveryTTL
+		if raw := strings.TrimSpace(os.Getenv("OOTB_DISCOVERY_TTL_SECONDS")); raw != "" {
+			if secs, err := strconv.Atoi(raw); err == nil && secs > 0 {
+				ttl = time.Duration(secs) * time.Second
+			} else {
+				log.Printf("getOOTBDiscoverer: ignoring invalid OOTB_DISCOVERY_TTL_SECONDS=%q", raw)
+			}
+		}
+		ootbDiscoverer = workflowcatalog.NewDiscoverer(cacheDir, ttl)
+		ootbDiscoverer.StartBackgroundRefresh(getOOTBCatalog(), ttl)
+	})
+	return ootbDiscoverer
+}
+
+// ListOOTBWorkflows returns the current catalog snapshot of out-of-the-box
+// workflows, enriched with metadata discovered from each workflow's own git
+// repo. Configuration comes from the catalog file (OOTB_CATALOG_PATH), with
+// the OOTB_SPEC_KIT_* and OOTB_BUG_FIX_* repo/branch/path environment
+// variables still honored as field-level overrides.
 func ListOOTBWorkflows(c *gin.Context) {
-	// Read OOTB workflow configuration from environment
-	specKitRepo := strings.TrimSpace(os.Getenv("OOTB_SPEC_KIT_REPO"))
-	if specKitRepo == "" {
-		specKitRepo = "https://github.com/Gkrumbach07/spec-kit-template.git"
-	}
-	specKitBranch := strings.TrimSpace(os.Getenv("OOTB_SPEC_KIT_BRANCH"))
-	if specKitBranch == "" {
-		specKitBranch = "main"
-	}
-	specKitPath := strings.TrimSpace(os.Getenv("OOTB_SPEC_KIT_PATH"))
-	if specKitPath == "" {
-		specKitPath = "workflows/spec-kit"
-	}
+	workflows := getOOTBDiscoverer().Discover(getOOTBCatalog().Snapshot())
+	c.JSON(http.StatusOK, gin.H{"workflows": workflows})
+}
 
-	bugFixRepo := strings.TrimSpace(os.Getenv("OOTB_BUG_FIX_REPO"))
-	bugFixBranch := strings.TrimSpace(os.Getenv("OOTB_BUG_FIX_BRANCH"))
-	if bugFixBranch == "" {
-		bugFixBranch = "main"
+// GetOOTBWorkflow returns a single catalog entry by id, enriched with
+// discovered manifest metadata.
+// GET /api/ootb-workflows/:id
+func GetOOTBWorkflow(c *gin.Context) {
+	id := c.Param("id")
+	entry, ok := getOOTBCatalog().Get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "OOTB workflow not found"})
+		return
 	}
-	bugFixPath := strings.TrimSpace(os.Getenv("OOTB_BUG_FIX_PATH"))
+	c.JSON(http.StatusOK, getOOTBDiscoverer().DiscoverOne(entry))
+}
 
-	workflows := []OOTBWorkflow{
-		{
-			ID:          "spec-kit",
-			Name:        "Spec Kit Workflow",
-			Description: "Comprehensive workflow for planning and implementing features using a specification-first approach",
-			GitURL:      specKitRepo,
-			Branch:      specKitBranch,
-			Path:        specKitPath,
-			Enabled:     true,
-		},
-		{
-			ID:          "bug-fix",
-			Name:        "Bug Fix Workflow",
-			Description: "Streamlined workflow for bug triage, reproduction, and fixes (Coming Soon)",
-			GitURL:      bugFixRepo,
-			Branch:      bugFixBranch,
-			Path:        bugFixPath,
-			Enabled:     bugFixRepo != "", // Only enabled if configured
-		},
+// RefreshOOTBWorkflow forces re-discovery of a single workflow's manifest,
+// bypassing the discovery TTL.
+// POST /api/ootb-workflows/:id/refresh
+func RefreshOOTBWorkflow(c *gin.Context) {
+	id := c.Param("id")
+	entry, ok := getOOTBCatalog().Get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "OOTB workflow not found"})
+		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"workflows": workflows})
+	c.JSON(http.StatusOK, getOOTBDiscoverer().Refresh(entry))
 }
 
+// ReloadOOTBWorkflows forces a re-read of the catalog file, for admins who
+// don't want to wait on the filesystem watcher (or are running somewhere
+// fsnotify can't watch, e.g. certain network mounts).
+// POST /api/ootb-workflows/reload
+func ReloadOOTBWorkflows(c *gin.Context) {
+	if err := getOOTBCatalog().Reload(); err != nil {
+		log.Printf("ReloadOOTBWorkflows: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload workflow catalog"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"workflows": getOOTBCatalog().Snapshot()})
+}
@@ -0,0 +1,42 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func init() {
+	Register(&exportAdapter{})
+}
+
+// exportAdapter handles the payload produced by
+// websocket.HandleExportSession: a JSON object with an "aguiEvents" array
+// (and, optionally, a "legacyMessages" array that has already been
+// migrated by an earlier export). This is the default, preferred format.
+type exportAdapter struct{}
+
+func (a *exportAdapter) Name() string { return "export" }
+
+func (a *exportAdapter) Detect(raw []byte) bool {
+	var probe struct {
+		AGUIEvents json.RawMessage `json:"aguiEvents"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return false
+	}
+	return len(probe.AGUIEvents) > 0
+}
+
+func (a *exportAdapter) Import(raw []byte) ([][]byte, error) {
+	var payload struct {
+		AGUIEvents []json.RawMessage `json:"aguiEvents"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("export format: %w", err)
+	}
+	events := make([][]byte, len(payload.AGUIEvents))
+	for i, e := range payload.AGUIEvents {
+		events[i] = []byte(e)
+	}
+	return events, nil
+}
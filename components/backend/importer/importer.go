@@ -0,0 +1,79 @@
+// Package importer loads session event logs from various on-disk or
+// uploaded formats back into the AG-UI NDJSON shape the rest of the
+// backend expects, so archived or exported sessions can be rehydrated.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Adapter converts a format-specific payload into a slice of AG-UI event
+// lines (each a standalone JSON object; whitespace from the source payload
+// may be kept, and RewriteSessionID re-marshals them). New formats register
+// themselves via Register in an init() function.
+type Adapter interface {
+	// Name identifies the format for the ?format= query param and for
+	// reporting which adapter handled a request.
+	Name() string
+	// Detect sniffs raw to decide whether this adapter can handle it.
+	// Detect is only consulted when the caller didn't pin a format.
+	Detect(raw []byte) bool
+	// Import parses raw and returns one JSON object per AG-UI
+	// event, in the order they should be replayed.
+	Import(raw []byte) ([][]byte, error)
+}
+
+var registry []Adapter
+
+// Register adds an adapter to the set considered by Resolve. It is meant
+// to be called from adapter init() functions, not at request time.
+func Register(a Adapter) {
+	registry = append(registry, a)
+}
+
+// Resolve returns the adapter to use for a request: the one named by
+// format if given, otherwise the first registered adapter whose Detect
+// recognizes raw.
+func Resolve(format string, raw []byte) (Adapter, error) {
+	if format != "" {
+		for _, a := range registry {
+			if a.Name() == format {
+				return a, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+	for _, a := range registry {
+		if a.Detect(raw) {
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("could not detect import format; pass ?format= explicitly")
+}
+
+// RewriteSessionID sets the sessionId field on every event to sessionName,
+// overwriting whatever session id the source format carried.
+//
+// Decoding uses UseNumber so large ids/timestamps/token counts (anything
+// above 2^53) round-trip through re-marshaling verbatim instead of being
+// silently rounded by decoding into float64.
+func RewriteSessionID(events [][]byte, sessionName string) ([][]byte, error) {
+	out := make([][]byte, len(events))
+	for i, raw := range events {
+		var obj map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+		obj["sessionId"] = sessionName
+		rewritten, err := json.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+		out[i] = rewritten
+	}
+	return out, nil
+}
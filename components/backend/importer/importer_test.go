@@ -0,0 +1,31 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRewriteSessionIDPreservesLargeNumbers ensures ids/timestamps/token
+// counts above 2^53 survive the sessionId rewrite verbatim instead of being
+// rounded by a float64 decode.
+func TestRewriteSessionIDPreservesLargeNumbers(t *testing.T) {
+	events := [][]byte{
+		[]byte(`{"type":"TEXT_MESSAGE","id":1700000000000000123,"tokenCount":9007199254740993}`),
+	}
+
+	out, err := RewriteSessionID(events, "new-session")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := string(out[0])
+	if !strings.Contains(got, `"id":1700000000000000123`) {
+		t.Errorf("large id was not preserved verbatim, got: %s", got)
+	}
+	if !strings.Contains(got, `"tokenCount":9007199254740993`) {
+		t.Errorf("large tokenCount was not preserved verbatim, got: %s", got)
+	}
+	if !strings.Contains(got, `"sessionId":"new-session"`) {
+		t.Errorf("sessionId was not rewritten, got: %s", got)
+	}
+}
@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+func init() {
+	Register(&legacyMessagesAdapter{})
+}
+
+// legacyMessage is the shape of a single line in the pre-AG-UI
+// messages.jsonl chat log (see websocket.HandleExportSession's
+// legacyMigratedPath/legacyOriginalPath handling).
+type legacyMessage struct {
+	Role      string `json:"role"`
+	Content   string `json:"content"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// legacyMessagesAdapter upgrades the old messages.jsonl chat format into
+// AG-UI TEXT_MESSAGE events so archived pre-AG-UI sessions can still be
+// imported.
+type legacyMessagesAdapter struct{}
+
+func (a *legacyMessagesAdapter) Name() string { return "messages-jsonl" }
+
+func (a *legacyMessagesAdapter) Detect(raw []byte) bool {
+	line, _, _ := bufio.NewReader(bytes.NewReader(raw)).ReadLine()
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return false
+	}
+	// A raw AG-UI event always carries a top-level "type" (see
+	// rawAGUIAdapter.Detect); messages.jsonl never does. Without this check
+	// a raw stream whose first line is a TEXT_MESSAGE (role+content, the
+	// shape Import below produces) would be misdetected as messages-jsonl.
+	var probe map[string]interface{}
+	if err := json.Unmarshal(line, &probe); err != nil {
+		return false
+	}
+	if _, hasType := probe["type"]; hasType {
+		return false
+	}
+	var msg legacyMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return false
+	}
+	return msg.Role != "" && msg.Content != ""
+}
+
+func (a *legacyMessagesAdapter) Import(raw []byte) ([][]byte, error) {
+	var events [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg legacyMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			return nil, fmt.Errorf("messages-jsonl format: %w", err)
+		}
+		event, err := json.Marshal(map[string]interface{}{
+			"type":      "TEXT_MESSAGE",
+			"role":      msg.Role,
+			"content":   msg.Content,
+			"timestamp": msg.Timestamp,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("messages-jsonl format: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("messages-jsonl format: %w", err)
+	}
+	return events, nil
+}
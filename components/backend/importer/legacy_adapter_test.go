@@ -0,0 +1,21 @@
+package importer
+
+import "testing"
+
+// TestLegacyMessagesAdapterDetectIgnoresTypedEvents ensures a raw AG-UI
+// TEXT_MESSAGE event (which also carries role+content, as
+// legacyMessagesAdapter.Import produces) is not misdetected as the legacy messages.jsonl
+// format just because it has those same fields.
+func TestLegacyMessagesAdapterDetectIgnoresTypedEvents(t *testing.T) {
+	a := &legacyMessagesAdapter{}
+
+	rawEvent := []byte(`{"type":"TEXT_MESSAGE","role":"user","content":"hello"}`)
+	if a.Detect(rawEvent) {
+		t.Errorf("Detect(%s) = true, want false (has a type field)", rawEvent)
+	}
+
+	legacyLine := []byte(`{"role":"user","content":"hello"}`)
+	if !a.Detect(legacyLine) {
+		t.Errorf("Detect(%s) = false, want true (no type field)", legacyLine)
+	}
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+func init() {
+	Register(&rawAGUIAdapter{})
+}
+
+// rawAGUIAdapter handles a bare AG-UI NDJSON stream with no wrapping
+// envelope, i.e. the same bytes agui-events.jsonl already holds on disk.
+// It's registered after the "export" and "messages-jsonl" adapters, and
+// Detect accepts a stream of JSON objects one per line only when the first
+// line carries a top-level "type" field.
+type rawAGUIAdapter struct{}
+
+func (a *rawAGUIAdapter) Name() string { return "raw-agui" }
+
+func (a *rawAGUIAdapter) Detect(raw []byte) bool {
+	line, _, _ := bufio.NewReader(bytes.NewReader(raw)).ReadLine()
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return false
+	}
+	var probe map[string]interface{}
+	if err := json.Unmarshal(line, &probe); err != nil {
+		return false
+	}
+	_, hasType := probe["type"]
+	return hasType
+}
+
+func (a *rawAGUIAdapter) Import(raw []byte) ([][]byte, error) {
+	var events [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return nil, fmt.Errorf("raw-agui format: invalid JSON line: %s", truncate(line, 80))
+		}
+		events = append(events, append([]byte(nil), line...))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("raw-agui format: %w", err)
+	}
+	return events, nil
+}
+
+func truncate(b []byte, n int) []byte {
+	if len(b) <= n {
+		return b
+	}
+	return b[:n]
+}
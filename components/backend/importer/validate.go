@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// knownFields are the top-level AG-UI event fields the rest of the backend
+// already understands. Anything else is surfaced as an "unknown field" in
+// the validation report so operators can spot drift in imported data.
+var knownFields = map[string]bool{
+	"type":      true,
+	"sessionId": true,
+	"timestamp": true,
+	"id":        true,
+	"role":      true,
+	"content":   true,
+	"data":      true,
+}
+
+// ValidationReport summarizes an import payload without writing anything
+// to disk, for use with ?validate=true.
+type ValidationReport struct {
+	TotalEvents       int            `json:"totalEvents"`
+	EventCountsByType map[string]int `json:"eventCountsByType"`
+	EarliestTimestamp string         `json:"earliestTimestamp,omitempty"`
+	LatestTimestamp   string         `json:"latestTimestamp,omitempty"`
+	UnknownFields     []string       `json:"unknownFields,omitempty"`
+}
+
+// Validate inspects a slice of AG-UI event lines and reports event counts
+// by type, the timestamp range, and any top-level fields it doesn't
+// recognize. It does not reject unknown fields; imports are expected to
+// evolve ahead of this list. It does reject events missing their required
+// "type" field, timestamps that run backwards, and a RUN_FINISHED that
+// doesn't close an open RUN_STARTED, since those indicate a corrupt or
+// out-of-order stream rather than a forwards-compatible field.
+func Validate(events [][]byte) (ValidationReport, error) {
+	report := ValidationReport{
+		EventCountsByType: make(map[string]int),
+	}
+
+	unknown := make(map[string]bool)
+	var lastTimestamp time.Time
+	var lastTimestampRaw string
+	runOpen := false
+	for i, raw := range events {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return report, err
+		}
+
+		report.TotalEvents++
+
+		eventType, _ := obj["type"].(string)
+		if eventType == "" {
+			return report, fmt.Errorf("event %d: missing required field %q", i, "type")
+		}
+		report.EventCountsByType[eventType]++
+
+		if ts, ok := obj["timestamp"].(string); ok && ts != "" {
+			// Parse rather than compare raw strings: mixed RFC3339
+			// precision/offsets don't sort lexically the same as
+			// chronologically (e.g. "...:00.5Z" < "...:00Z" as strings).
+			// A timestamp we can't parse is left out of the ordering check
+			// entirely rather than rejected, since the report's job is to
+			// flag structural problems, not enforce a specific format.
+			parsed, err := time.Parse(time.RFC3339Nano, ts)
+			if err == nil {
+				if !lastTimestamp.IsZero() && parsed.Before(lastTimestamp) {
+					return report, fmt.Errorf("event %d: timestamp %q is out of order (after %q)", i, ts, lastTimestampRaw)
+				}
+				lastTimestamp = parsed
+				lastTimestampRaw = ts
+			}
+			if report.EarliestTimestamp == "" {
+				report.EarliestTimestamp = ts
+			}
+			report.LatestTimestamp = ts
+		}
+
+		switch eventType {
+		case "RUN_STARTED":
+			if runOpen {
+				return report, fmt.Errorf("event %d: RUN_STARTED while a previous run is still open", i)
+			}
+			runOpen = true
+		case "RUN_FINISHED":
+			if !runOpen {
+				return report, fmt.Errorf("event %d: RUN_FINISHED without a matching RUN_STARTED", i)
+			}
+			runOpen = false
+		}
+
+		for field := range obj {
+			if !knownFields[field] {
+				unknown[field] = true
+			}
+		}
+	}
+
+	for field := range unknown {
+		report.UnknownFields = append(report.UnknownFields, field)
+	}
+	sort.Strings(report.UnknownFields)
+
+	return report, nil
+}
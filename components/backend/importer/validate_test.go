@@ -0,0 +1,55 @@
+package importer
+
+import "testing"
+
+func TestValidateRejectsMissingType(t *testing.T) {
+	events := [][]byte{[]byte(`{"sessionId":"s1"}`)}
+	if _, err := Validate(events); err == nil {
+		t.Fatal("expected error for event missing type, got nil")
+	}
+}
+
+func TestValidateRejectsOutOfOrderTimestamps(t *testing.T) {
+	events := [][]byte{
+		[]byte(`{"type":"TEXT_MESSAGE","timestamp":"2026-01-02T00:00:00Z"}`),
+		[]byte(`{"type":"TEXT_MESSAGE","timestamp":"2026-01-01T00:00:00Z"}`),
+	}
+	if _, err := Validate(events); err == nil {
+		t.Fatal("expected error for out-of-order timestamps, got nil")
+	}
+}
+
+func TestValidateAcceptsMixedTimestampPrecision(t *testing.T) {
+	// Lexically, "...:00.5Z" < "...:00Z", even though it's later
+	// chronologically - a string comparison would misflag this as
+	// out-of-order.
+	events := [][]byte{
+		[]byte(`{"type":"TEXT_MESSAGE","timestamp":"2026-01-01T00:00:00Z"}`),
+		[]byte(`{"type":"TEXT_MESSAGE","timestamp":"2026-01-01T00:00:00.5Z"}`),
+	}
+	if _, err := Validate(events); err != nil {
+		t.Fatalf("unexpected error for chronologically-ordered mixed-precision timestamps: %v", err)
+	}
+}
+
+func TestValidateRejectsUnmatchedRunFinished(t *testing.T) {
+	events := [][]byte{[]byte(`{"type":"RUN_FINISHED"}`)}
+	if _, err := Validate(events); err == nil {
+		t.Fatal("expected error for RUN_FINISHED without RUN_STARTED, got nil")
+	}
+}
+
+func TestValidateAcceptsWellFormedStream(t *testing.T) {
+	events := [][]byte{
+		[]byte(`{"type":"RUN_STARTED","timestamp":"2026-01-01T00:00:00Z"}`),
+		[]byte(`{"type":"TEXT_MESSAGE","timestamp":"2026-01-01T00:00:01Z","role":"user","content":"hi"}`),
+		[]byte(`{"type":"RUN_FINISHED","timestamp":"2026-01-01T00:00:02Z"}`),
+	}
+	report, err := Validate(events)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.TotalEvents != 3 {
+		t.Errorf("expected 3 total events, got %d", report.TotalEvents)
+	}
+}
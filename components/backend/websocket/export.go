@@ -2,17 +2,25 @@
 package websocket
 
 import (
+	"archive/zip"
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/Gkrumbach07/platform/components/backend/provenance"
 )
 
-// ExportResponse contains the exported session data
+// ExportResponse documents the JSON schema HandleExportSession writes to the
+// response body. The handler itself streams these fields directly rather
+// than building this struct, but callers (e.g. the session importer) can
+// still unmarshal into it.
 type ExportResponse struct {
 	SessionID      string          `json:"sessionId"`
 	ProjectName    string          `json:"projectName"`
@@ -22,7 +30,11 @@ type ExportResponse struct {
 	HasLegacy      bool            `json:"hasLegacy"`
 }
 
-// HandleExportSession exports session chat data as JSON
+// HandleExportSession exports session chat data as JSON, streaming
+// agui-events.jsonl (and legacy messages.jsonl, if present) straight into
+// the response body one line at a time. It never slurps the whole event
+// log into memory, so it stays on top of the same storage the chunked
+// exporter (HandleStartExport) uses for large/long-running sessions.
 // GET /api/projects/:projectName/agentic-sessions/:sessionName/export
 func HandleExportSession(c *gin.Context) {
 	projectName := c.Param("projectName")
@@ -43,36 +55,6 @@ func HandleExportSession(c *gin.Context) {
 		return
 	}
 
-	response := ExportResponse{
-		SessionID:   sessionName,
-		ProjectName: projectName,
-		ExportDate:  time.Now().UTC().Format(time.RFC3339),
-		HasLegacy:   false,
-	}
-
-	// Read AG-UI events
-	aguiData, err := readJSONLFile(aguiEventsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// No AG-UI events yet - return empty array
-			response.AGUIEvents = json.RawMessage("[]")
-		} else {
-			log.Printf("Export: Error reading AG-UI events: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read session events"})
-			return
-		}
-	} else {
-		// Pretty-print the events array
-		prettyJSON, err := json.MarshalIndent(aguiData, "", "  ")
-		if err != nil {
-			log.Printf("Export: Error formatting AG-UI events: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to format events"})
-			return
-		}
-		response.AGUIEvents = prettyJSON
-	}
-
-	// Check for legacy messages - try migrated file first, then original
 	legacyPath := ""
 	if _, err := os.Stat(legacyMigratedPath); err == nil {
 		legacyPath = legacyMigratedPath
@@ -81,54 +63,131 @@ func HandleExportSession(c *gin.Context) {
 		legacyPath = legacyOriginalPath
 		log.Printf("Export: Found original legacy file: %s", legacyOriginalPath)
 	}
+	hasLegacy := legacyPath != ""
+
+	manifestFormat := c.Query("manifest")
+	if manifestFormat == "" {
+		c.Header("Content-Type", "application/json")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-export.json\"", sessionName))
+		c.Status(http.StatusOK)
+		writeExportJSON(c.Writer, projectName, sessionName, aguiEventsPath, legacyPath, hasLegacy)
+		log.Printf("Export: Successfully exported session %s (hasLegacy=%v)", sessionName, hasLegacy)
+		return
+	}
 
-	if legacyPath != "" {
-		legacyData, err := readJSONLFile(legacyPath)
-		if err != nil {
-			log.Printf("Export: Warning - failed to read legacy messages: %v", err)
-		} else {
-			prettyJSON, err := json.MarshalIndent(legacyData, "", "  ")
-			if err != nil {
-				log.Printf("Export: Warning - failed to format legacy messages: %v", err)
-			} else {
-				response.LegacyMessages = prettyJSON
-				response.HasLegacy = true
-			}
-		}
+	if manifestFormat != "cyclonedx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported manifest format %q (only \"cyclonedx\" is supported)", manifestFormat)})
+		return
+	}
+
+	manifest, err := provenance.BuildCycloneDX(aguiEventsPath)
+	if err != nil {
+		log.Printf("Export: failed to build provenance manifest for %s: %v", sessionName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build provenance manifest"})
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-export-bundle.zip\"", sessionName))
+	c.Status(http.StatusOK)
+
+	zw := zip.NewWriter(c.Writer)
+	exportEntry, err := zw.Create(fmt.Sprintf("%s-export.json", sessionName))
+	if err != nil {
+		log.Printf("Export: failed to create zip entry for export: %v", err)
+		return
 	}
+	writeExportJSON(exportEntry, projectName, sessionName, aguiEventsPath, legacyPath, hasLegacy)
 
-	log.Printf("Export: Successfully exported session %s (hasLegacy=%v)", sessionName, response.HasLegacy)
+	manifestEntry, err := zw.Create(fmt.Sprintf("%s-manifest.cdx.json", sessionName))
+	if err != nil {
+		log.Printf("Export: failed to create zip entry for manifest: %v", err)
+		return
+	}
+	if err := json.NewEncoder(manifestEntry).Encode(manifest); err != nil {
+		log.Printf("Export: failed to write provenance manifest: %v", err)
+	}
 
-	// Set headers for JSON download
-	c.Header("Content-Type", "application/json")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-export.json\"", sessionName))
+	if err := zw.Close(); err != nil {
+		log.Printf("Export: failed to finalize export bundle for %s: %v", sessionName, err)
+	}
+	log.Printf("Export: Successfully exported session %s with %s manifest (%d components)", sessionName, manifestFormat, len(manifest.Components))
+}
+
+// writeExportJSON streams the same JSON object HandleExportSession has
+// always returned - sessionId, projectName, exportDate, aguiEvents,
+// hasLegacy, legacyMessages - into w, without holding the events in memory.
+func writeExportJSON(w io.Writer, projectName, sessionName, aguiEventsPath, legacyPath string, hasLegacy bool) {
+	io.WriteString(w, "{")
+	writeJSONField(w, "sessionId", sessionName, true)
+	writeJSONField(w, "projectName", projectName, false)
+	writeJSONField(w, "exportDate", time.Now().UTC().Format(time.RFC3339), false)
+
+	io.WriteString(w, `,"aguiEvents":`)
+	if err := streamJSONLArray(w, aguiEventsPath); err != nil {
+		log.Printf("Export: Error streaming AG-UI events: %v", err)
+	}
 
-	c.JSON(http.StatusOK, response)
+	writeJSONField(w, "hasLegacy", hasLegacy, false)
+
+	if hasLegacy {
+		io.WriteString(w, `,"legacyMessages":`)
+		if err := streamJSONLArray(w, legacyPath); err != nil {
+			log.Printf("Export: Warning - failed to stream legacy messages: %v", err)
+		}
+	}
+
+	io.WriteString(w, "}")
+}
+
+// writeJSONField writes a single "name":value pair, preceded by a comma
+// unless first is true, marshaling value so strings/bools are escaped
+// correctly.
+func writeJSONField(w io.Writer, name string, value interface{}, first bool) {
+	if !first {
+		io.WriteString(w, ",")
+	}
+	fmt.Fprintf(w, "%q:", name)
+	keyBytes, _ := json.Marshal(value)
+	w.Write(keyBytes)
 }
 
-// readJSONLFile reads a JSONL file and returns parsed array of objects
-func readJSONLFile(path string) ([]map[string]interface{}, error) {
-	data, err := os.ReadFile(path)
+// streamJSONLArray copies each valid JSON line of a JSONL file into w as a
+// JSON array, skipping malformed lines, without ever holding the full file
+// or the full array in memory at once. If path does not exist, it writes
+// an empty array.
+func streamJSONLArray(w io.Writer, path string) error {
+	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		if os.IsNotExist(err) {
+			io.WriteString(w, "[]")
+			return nil
+		}
+		io.WriteString(w, "[]")
+		return err
 	}
+	defer f.Close()
 
-	var events []map[string]interface{}
-	lines := splitLines(data)
+	io.WriteString(w, "[")
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
 
-	for _, line := range lines {
+	wroteAny := false
+	for scanner.Scan() {
+		line := scanner.Bytes()
 		if len(line) == 0 {
 			continue
 		}
-		var event map[string]interface{}
-		if err := json.Unmarshal(line, &event); err != nil {
-			// Skip malformed lines
-			log.Printf("Export: Skipping malformed JSON line: %v", err)
+		if !json.Valid(line) {
+			log.Printf("Export: Skipping malformed JSON line in %s", path)
 			continue
 		}
-		events = append(events, event)
+		if wroteAny {
+			io.WriteString(w, ",")
+		}
+		w.Write(line)
+		wroteAny = true
 	}
-
-	return events, nil
+	io.WriteString(w, "]")
+	return scanner.Err()
 }
-
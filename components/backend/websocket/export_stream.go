@@ -0,0 +1,439 @@
+package websocket
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportChunkSize is the size, in bytes, of a single chunk served by
+// GET /api/exports/:exportId?chunk=N.
+const exportChunkSize = 8 << 20 // 8 MiB
+
+// exportArtifactTTL is how long a completed export artifact is kept on disk
+// before the GC goroutine removes it, unless overridden by
+// EXPORT_ARTIFACT_TTL_SECONDS.
+const exportArtifactTTL = 30 * time.Minute
+
+// exportJobStatus values.
+const (
+	ExportStatusPending = "pending"
+	ExportStatusRunning = "running"
+	ExportStatusDone    = "done"
+	ExportStatusFailed  = "failed"
+)
+
+// exportJob tracks a single background export's progress and resulting
+// artifact on disk.
+type exportJob struct {
+	mu sync.Mutex
+
+	ID          string
+	ProjectName string
+	SessionName string
+	Status      string
+	Error       string
+
+	Path       string
+	Size       int64
+	SHA256     string
+	ChunkCount int
+
+	CreatedAt   time.Time
+	CompletedAt time.Time
+}
+
+func (j *exportJob) snapshot() gin.H {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return gin.H{
+		"exportId":   j.ID,
+		"status":     j.Status,
+		"error":      j.Error,
+		"size":       j.Size,
+		"sha256":     j.SHA256,
+		"chunkCount": j.ChunkCount,
+		"createdAt":  j.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// exportRegistry holds all known export jobs in memory. Artifacts live on
+// disk under exportRegistry.dir; the registry itself is rebuilt on restart
+// (in-flight exports are lost, which is acceptable since clients poll
+// status and can simply re-request the export).
+type exportRegistry struct {
+	mu       sync.Mutex
+	jobs     map[string]*exportJob
+	dir      string
+	gcOnce   sync.Once
+	artifact time.Duration
+}
+
+var defaultExportRegistry = newExportRegistry()
+
+func newExportRegistry() *exportRegistry {
+	dir := os.TempDir() + "/platform-exports"
+	_ = os.MkdirAll(dir, 0o755)
+
+	ttl := exportArtifactTTL
+	if raw := os.Getenv("EXPORT_ARTIFACT_TTL_SECONDS"); raw != "" {
+		if secs, err := strconv.Atoi(raw); err == nil && secs > 0 {
+			ttl = time.Duration(secs) * time.Second
+		}
+	}
+
+	return &exportRegistry{
+		jobs:     make(map[string]*exportJob),
+		dir:      dir,
+		artifact: ttl,
+	}
+}
+
+func (r *exportRegistry) create(projectName, sessionName string) *exportJob {
+	job := &exportJob{
+		ID:          generateExportID(),
+		ProjectName: projectName,
+		SessionName: sessionName,
+		Status:      ExportStatusPending,
+		CreatedAt:   time.Now(),
+	}
+	job.Path = fmt.Sprintf("%s/%s.ndjson.gz", r.dir, job.ID)
+
+	r.mu.Lock()
+	r.jobs[job.ID] = job
+	r.mu.Unlock()
+
+	r.startGC()
+	return job
+}
+
+func (r *exportRegistry) get(id string) (*exportJob, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	return job, ok
+}
+
+// startGC launches (once per process) a goroutine that periodically removes
+// completed export artifacts older than the configured TTL.
+func (r *exportRegistry) startGC() {
+	r.gcOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(r.artifact / 2)
+			defer ticker.Stop()
+			for range ticker.C {
+				r.sweep()
+			}
+		}()
+	})
+}
+
+func (r *exportRegistry) sweep() {
+	cutoff := time.Now().Add(-r.artifact)
+
+	r.mu.Lock()
+	var stale []*exportJob
+	for id, job := range r.jobs {
+		job.mu.Lock()
+		done := job.Status == ExportStatusDone || job.Status == ExportStatusFailed
+		completedAt := job.CompletedAt
+		job.mu.Unlock()
+		if done && completedAt.Before(cutoff) {
+			stale = append(stale, job)
+			delete(r.jobs, id)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, job := range stale {
+		if err := os.Remove(job.Path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Export: failed to GC artifact %s: %v", job.Path, err)
+		}
+	}
+}
+
+func generateExportID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand failing is effectively unheard of; fall back to a
+		// timestamp so we never hand back an empty id.
+		return fmt.Sprintf("export-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// HandleStartExport kicks off a background job that streams the session's
+// AG-UI event log into a gzipped NDJSON artifact, and returns immediately
+// with the export id and the job's current status. The response carries no
+// size, chunk count or sha256; HandleExportStatus reports those once the
+// job is done.
+// POST /api/projects/:projectName/agentic-sessions/:sessionName/export
+func HandleStartExport(c *gin.Context) {
+	projectName := c.Param("projectName")
+	sessionName := c.Param("sessionName")
+
+	sessionDir := fmt.Sprintf("%s/sessions/%s", StateBaseDir, sessionName)
+	aguiEventsPath := fmt.Sprintf("%s/agui-events.jsonl", sessionDir)
+	if _, err := os.Stat(sessionDir); os.IsNotExist(err) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	job := defaultExportRegistry.create(projectName, sessionName)
+	log.Printf("Export: started job %s for %s/%s", job.ID, projectName, sessionName)
+
+	go runExportJob(job, aguiEventsPath)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"exportId": job.ID,
+		"status":   job.Status,
+	})
+}
+
+// runExportJob streams aguiEventsPath into the job's gzipped NDJSON
+// artifact line-by-line, never holding the whole file in memory.
+func runExportJob(job *exportJob, aguiEventsPath string) {
+	job.mu.Lock()
+	job.Status = ExportStatusRunning
+	job.mu.Unlock()
+
+	if err := streamNDJSONGzip(aguiEventsPath, job.Path); err != nil {
+		log.Printf("Export: job %s failed: %v", job.ID, err)
+		job.mu.Lock()
+		job.Status = ExportStatusFailed
+		job.Error = err.Error()
+		job.CompletedAt = time.Now()
+		job.mu.Unlock()
+		return
+	}
+
+	size, sum, err := fileSizeAndSHA256(job.Path)
+	if err != nil {
+		log.Printf("Export: job %s failed hashing artifact: %v", job.ID, err)
+		job.mu.Lock()
+		job.Status = ExportStatusFailed
+		job.Error = err.Error()
+		job.CompletedAt = time.Now()
+		job.mu.Unlock()
+		return
+	}
+
+	job.mu.Lock()
+	job.Status = ExportStatusDone
+	job.Size = size
+	job.SHA256 = sum
+	job.ChunkCount = int((size + exportChunkSize - 1) / exportChunkSize)
+	job.CompletedAt = time.Now()
+	job.mu.Unlock()
+	log.Printf("Export: job %s complete (%d bytes, %d chunks)", job.ID, size, job.ChunkCount)
+}
+
+// streamNDJSONGzip copies each valid JSON line of src into a gzip-compressed
+// NDJSON file at dstPath, skipping malformed lines the same way the
+// one-shot export does. It streams line-by-line so memory use stays
+// constant regardless of file size.
+func streamNDJSONGzip(srcPath, dstPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// No events yet; still produce an empty artifact.
+			return writeEmptyGzip(dstPath)
+		}
+		return fmt.Errorf("opening %s: %w", srcPath, err)
+	}
+	defer src.Close()
+
+	tmpPath := dstPath + ".tmp"
+	dst, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", tmpPath, err)
+	}
+
+	gw := gzip.NewWriter(dst)
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			log.Printf("Export: skipping malformed JSON line in %s", srcPath)
+			continue
+		}
+		if _, err := gw.Write(line); err != nil {
+			dst.Close()
+			return fmt.Errorf("writing ndjson: %w", err)
+		}
+		if _, err := gw.Write([]byte("\n")); err != nil {
+			dst.Close()
+			return fmt.Errorf("writing ndjson: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		dst.Close()
+		return fmt.Errorf("scanning %s: %w", srcPath, err)
+	}
+
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		return fmt.Errorf("closing gzip writer: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", tmpPath, err)
+	}
+	return os.Rename(tmpPath, dstPath)
+}
+
+func writeEmptyGzip(dstPath string) error {
+	tmpPath := dstPath + ".tmp"
+	dst, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(dst)
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, dstPath)
+}
+
+func fileSizeAndSHA256(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HandleExportStatus reports progress for a background export job.
+// GET /api/exports/:exportId/status
+func HandleExportStatus(c *gin.Context) {
+	job, ok := defaultExportRegistry.get(c.Param("exportId"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+	c.JSON(http.StatusOK, job.snapshot())
+}
+
+// HandleExportDownload serves the completed export artifact, in full or in
+// a byte range selected by either ?chunk=N or ?rangeStart=..&rangeEnd=..,
+// so a dropped connection can resume instead of restarting the download.
+// GET /api/exports/:exportId
+func HandleExportDownload(c *gin.Context) {
+	job, ok := defaultExportRegistry.get(c.Param("exportId"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+
+	job.mu.Lock()
+	status := job.Status
+	size := job.Size
+	job.mu.Unlock()
+
+	if status != ExportStatusDone {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Export is %s, not ready yet", status)})
+		return
+	}
+
+	f, err := os.Open(job.Path)
+	if err != nil {
+		log.Printf("Export: failed to open artifact %s: %v", job.Path, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read export artifact"})
+		return
+	}
+	defer f.Close()
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-export.ndjson.gz\"", job.SessionName))
+	c.Header("X-Export-SHA256", job.SHA256)
+
+	start, end, ok := parseRequestedRange(c, size)
+	if !ok {
+		c.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"error": "Invalid range"})
+		return
+	}
+	if start == 0 && end == size-1 {
+		// No partial range requested: serve the whole artifact, letting
+		// http.ServeContent still honor a standard Range header.
+		http.ServeContent(c.Writer, c.Request, job.Path, job.CompletedAt, f)
+		return
+	}
+
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to seek export artifact"})
+		return
+	}
+	length := end - start + 1
+	c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	c.Header("Content-Length", strconv.FormatInt(length, 10))
+	c.Status(http.StatusPartialContent)
+	if _, err := io.CopyN(c.Writer, f, length); err != nil {
+		log.Printf("Export: error streaming range %d-%d of %s: %v", start, end, job.Path, err)
+	}
+}
+
+// parseRequestedRange resolves the requested byte range from either the
+// ?chunk=N or ?rangeStart=&rangeEnd= query params, defaulting to the whole
+// file. ok is false if the requested range is out of bounds.
+func parseRequestedRange(c *gin.Context, size int64) (start, end int64, ok bool) {
+	if chunkParam := c.Query("chunk"); chunkParam != "" {
+		n, err := strconv.Atoi(chunkParam)
+		if err != nil || n < 0 {
+			return 0, 0, false
+		}
+		start = int64(n) * exportChunkSize
+		end = start + exportChunkSize - 1
+	} else if rs := c.Query("rangeStart"); rs != "" {
+		s, err := strconv.ParseInt(rs, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		start = s
+		end = size - 1
+		if re := c.Query("rangeEnd"); re != "" {
+			e, err := strconv.ParseInt(re, 10, 64)
+			if err != nil {
+				return 0, 0, false
+			}
+			end = e
+		}
+	} else {
+		return 0, size - 1, true
+	}
+
+	if end >= size {
+		end = size - 1
+	}
+	if start < 0 || start > end {
+		return 0, 0, false
+	}
+	return start, end, true
+}
@@ -0,0 +1,222 @@
+// Package workflowcatalog loads and serves the catalog of out-of-the-box
+// (OOTB) agentic workflows offered to users. The catalog is backed by a
+// YAML file (ConfigMap-mountable in cluster deployments) so operators can
+// add, retag, or disable workflows without restarting the backend.
+package workflowcatalog
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// DefaultCatalogPath is used when OOTB_CATALOG_PATH is unset. In cluster
+// deployments this is expected to be a ConfigMap mount.
+const DefaultCatalogPath = "/etc/platform/ootb-catalog.yaml"
+
+// AuthRef points at a secret holding credentials for a private workflow repo.
+type AuthRef struct {
+	SecretName string `mapstructure:"secretName" json:"secretName"`
+	SecretKey  string `mapstructure:"secretKey" json:"secretKey,omitempty"`
+}
+
+// ParamSchema is a minimal JSON-Schema-like description of a workflow's
+// user-supplied parameters. It is intentionally permissive since the exact
+// shape is workflow-defined.
+type ParamSchema map[string]interface{}
+
+// Entry describes a single OOTB workflow available to users.
+type Entry struct {
+	ID          string      `mapstructure:"id" json:"id"`
+	Name        string      `mapstructure:"name" json:"name"`
+	Description string      `mapstructure:"description" json:"description"`
+	GitURL      string      `mapstructure:"gitUrl" json:"gitUrl"`
+	Branch      string      `mapstructure:"branch" json:"branch"`
+	Path        string      `mapstructure:"path" json:"path,omitempty"`
+	Enabled     bool        `mapstructure:"enabled" json:"enabled"`
+	Tags        []string    `mapstructure:"tags" json:"tags,omitempty"`
+	Icon        string      `mapstructure:"icon" json:"icon,omitempty"`
+	Category    string      `mapstructure:"category" json:"category,omitempty"`
+	Auth        *AuthRef    `mapstructure:"auth" json:"auth,omitempty"`
+	Params      ParamSchema `mapstructure:"params" json:"params,omitempty"`
+}
+
+// config is the top-level shape of the catalog file.
+type config struct {
+	Workflows []Entry `mapstructure:"workflows"`
+}
+
+// Catalog holds the current, loaded-and-validated set of OOTB workflow
+// entries and can watch its backing file for changes.
+type Catalog struct {
+	mu      sync.RWMutex
+	path    string
+	v       *viper.Viper
+	entries []Entry
+	watcher *fsnotify.Watcher
+}
+
+// Load reads and validates the catalog file at path, merging in defaults and
+// the legacy OOTB_SPEC_KIT_*/OOTB_BUG_FIX_* environment variable overrides
+// (see legacyEnvOverrides). If path does not exist, the
+// built-in defaults are used as-is so the platform keeps working without a
+// mounted ConfigMap.
+func Load(path string) (*Catalog, error) {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultCatalogPath
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	for key, val := range defaultSettings() {
+		v.SetDefault(key, val)
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("workflowcatalog: reading %s: %w", path, err)
+		}
+		log.Printf("workflowcatalog: no catalog file at %s, using defaults only", path)
+	}
+
+	c := &Catalog{path: path, v: v}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Snapshot returns a copy of the currently loaded entries.
+func (c *Catalog) Snapshot() []Entry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]Entry, len(c.entries))
+	copy(out, c.entries)
+	return out
+}
+
+// Get returns a single entry by id.
+func (c *Catalog) Get(id string) (Entry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, e := range c.entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Reload re-reads the catalog file from disk and validates it, replacing the
+// in-memory snapshot on success. A failed reload leaves the previous
+// snapshot in place.
+func (c *Catalog) Reload() error {
+	if err := c.v.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("workflowcatalog: reloading %s: %w", c.path, err)
+	}
+	return c.reload()
+}
+
+func (c *Catalog) reload() error {
+	var cfg config
+	if err := c.v.Unmarshal(&cfg); err != nil {
+		return fmt.Errorf("workflowcatalog: unmarshaling %s: %w", c.path, err)
+	}
+
+	applyLegacyEnvOverrides(cfg.Workflows)
+
+	if err := validate(cfg.Workflows); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.entries = cfg.Workflows
+	c.mu.Unlock()
+	return nil
+}
+
+// Watch starts watching the catalog file's directory for changes and
+// reloads on any write/create/rename/remove event there. It returns
+// immediately; the watch runs in a background goroutine until the process
+// exits or Close is called.
+//
+// It watches the directory rather than the file itself because Kubernetes
+// updates a mounted ConfigMap by atomically repointing a "..data" symlink
+// at a new timestamped directory: the inotify event fsnotify would see
+// lands on "..data" inside the mount dir, never on c.path, so a file-level
+// watch (or a handler that filters events down to event.Name == c.path)
+// never fires for the one deployment mode this is meant to support.
+func (c *Catalog) Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("workflowcatalog: starting watcher: %w", err)
+	}
+	dir := dirOf(c.path)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("workflowcatalog: watching %s: %w", dir, err)
+	}
+	c.watcher = watcher
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+					continue
+				}
+				log.Printf("workflowcatalog: detected change under %s, reloading", dir)
+				if err := c.Reload(); err != nil {
+					log.Printf("workflowcatalog: reload failed: %v", err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("workflowcatalog: watcher error: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops the background file watcher, if running.
+func (c *Catalog) Close() error {
+	if c.watcher == nil {
+		return nil
+	}
+	return c.watcher.Close()
+}
+
+func validate(entries []Entry) error {
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if strings.TrimSpace(e.ID) == "" {
+			return fmt.Errorf("workflowcatalog: entry %q is missing an id", e.Name)
+		}
+		if seen[e.ID] {
+			return fmt.Errorf("workflowcatalog: duplicate workflow id %q", e.ID)
+		}
+		seen[e.ID] = true
+		if e.Enabled && strings.TrimSpace(e.GitURL) == "" {
+			return fmt.Errorf("workflowcatalog: entry %q is missing gitUrl", e.ID)
+		}
+	}
+	return nil
+}
+
+func dirOf(path string) string {
+	idx := strings.LastIndex(path, "/")
+	if idx < 0 {
+		return "."
+	}
+	return path[:idx]
+}
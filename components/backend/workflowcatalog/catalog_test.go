@@ -0,0 +1,29 @@
+package workflowcatalog
+
+import "testing"
+
+// TestLoadDefaultsOnly ensures a cluster with no catalog file (and no
+// ConfigMap mount) can still start up: the built-in bug-fix default is
+// disabled with an empty gitUrl, which must not trip validation.
+func TestLoadDefaultsOnly(t *testing.T) {
+	c, err := Load("")
+	if err != nil {
+		t.Fatalf("Load(\"\") returned error: %v", err)
+	}
+
+	entries := c.Snapshot()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 default entries, got %d", len(entries))
+	}
+
+	bugFix, ok := c.Get("bug-fix")
+	if !ok {
+		t.Fatalf("expected default entry %q", "bug-fix")
+	}
+	if bugFix.Enabled {
+		t.Errorf("expected default %q entry to be disabled", "bug-fix")
+	}
+	if bugFix.GitURL != "" {
+		t.Errorf("expected default %q entry to have an empty gitUrl, got %q", "bug-fix", bugFix.GitURL)
+	}
+}
@@ -0,0 +1,85 @@
+package workflowcatalog
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultSettings seeds the viper instance with the two built-in workflows
+// that used to be hard-coded in handlers.ListOOTBWorkflows, so a cluster
+// with no catalog file (and no ConfigMap mount) keeps serving the same two
+// workflows.
+func defaultSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"workflows": []map[string]interface{}{
+			{
+				"id":          "spec-kit",
+				"name":        "Spec Kit Workflow",
+				"description": "Comprehensive workflow for planning and implementing features using a specification-first approach",
+				"gitUrl":      "https://github.com/Gkrumbach07/spec-kit-template.git",
+				"branch":      "main",
+				"path":        "workflows/spec-kit",
+				"enabled":     true,
+				"category":    "planning",
+			},
+			{
+				"id":          "bug-fix",
+				"name":        "Bug Fix Workflow",
+				"description": "Streamlined workflow for bug triage, reproduction, and fixes (Coming Soon)",
+				"gitUrl":      "",
+				"branch":      "main",
+				"path":        "",
+				"enabled":     false,
+				"category":    "maintenance",
+			},
+		},
+	}
+}
+
+// legacyEnvOverrides maps the pre-existing OOTB_SPEC_KIT_* and OOTB_BUG_FIX_*
+// environment variables to the (entry id, field) they override, since
+// operators already depend on setting these without touching the catalog.
+//
+// These are applied by id after the catalog file is unmarshaled rather than
+// bound through viper: viper's slice-index keys (e.g. "workflows.0.gitUrl")
+// don't survive Unmarshal into []Entry, since AllSettings() rebuilds "0" as
+// a map key instead of a slice index.
+var legacyEnvOverrides = map[string]struct {
+	id    string
+	field string
+}{
+	"OOTB_SPEC_KIT_REPO":   {"spec-kit", "gitUrl"},
+	"OOTB_SPEC_KIT_BRANCH": {"spec-kit", "branch"},
+	"OOTB_SPEC_KIT_PATH":   {"spec-kit", "path"},
+	"OOTB_BUG_FIX_REPO":    {"bug-fix", "gitUrl"},
+	"OOTB_BUG_FIX_BRANCH":  {"bug-fix", "branch"},
+	"OOTB_BUG_FIX_PATH":    {"bug-fix", "path"},
+}
+
+// applyLegacyEnvOverrides mutates entries in place, overriding the named
+// field of the matching entry id whenever its legacy env var is set to a
+// non-blank value (a blank value leaves the field as declared). Only that
+// field changes: the entry's enabled flag stays as the catalog declares it,
+// so setting OOTB_BUG_FIX_REPO alone does not enable the bug-fix entry.
+func applyLegacyEnvOverrides(entries []Entry) {
+	for env, target := range legacyEnvOverrides {
+		val, ok := os.LookupEnv(env)
+		val = strings.TrimSpace(val)
+		if !ok || val == "" {
+			continue
+		}
+		for i := range entries {
+			if entries[i].ID != target.id {
+				continue
+			}
+			switch target.field {
+			case "gitUrl":
+				entries[i].GitURL = val
+			case "branch":
+				entries[i].Branch = val
+			case "path":
+				entries[i].Path = val
+			}
+		}
+	}
+}
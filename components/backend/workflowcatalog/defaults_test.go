@@ -0,0 +1,22 @@
+package workflowcatalog
+
+import "testing"
+
+// TestLoadLegacyEnvOverride ensures the pre-existing OOTB_SPEC_KIT_* env
+// vars still override the matching default entry's field.
+func TestLoadLegacyEnvOverride(t *testing.T) {
+	t.Setenv("OOTB_SPEC_KIT_REPO", "https://example.com/custom-spec-kit.git")
+
+	c, err := Load("")
+	if err != nil {
+		t.Fatalf("Load(\"\") returned error: %v", err)
+	}
+
+	specKit, ok := c.Get("spec-kit")
+	if !ok {
+		t.Fatalf("expected default entry %q", "spec-kit")
+	}
+	if specKit.GitURL != "https://example.com/custom-spec-kit.git" {
+		t.Errorf("expected OOTB_SPEC_KIT_REPO to override gitUrl, got %q", specKit.GitURL)
+	}
+}
@@ -0,0 +1,292 @@
+package workflowcatalog
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// ManifestPath is the well-known location, relative to a workflow repo's
+// root, of the manifest discovery reads metadata from.
+const ManifestPath = ".platform/workflow.yaml"
+
+// Discovery statuses surfaced on each catalog entry.
+const (
+	DiscoveryOK              = "ok"
+	DiscoveryManifestMissing = "manifest-missing"
+	DiscoveryCloneFailed     = "clone-failed"
+)
+
+// Manifest is the shape of .platform/workflow.yaml inside a workflow repo.
+type Manifest struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Version     string   `yaml:"version"`
+	Icon        string   `yaml:"icon"`
+	Inputs      []string `yaml:"inputs"`
+	Entrypoints []string `yaml:"entrypoints"`
+}
+
+// DiscoveredEntry is a catalog Entry enriched with manifest-derived fields
+// and the outcome of the last discovery attempt.
+type DiscoveredEntry struct {
+	Entry
+	DiscoveryStatus string   `json:"discoveryStatus"`
+	DiscoveryError  string   `json:"discoveryError,omitempty"`
+	CommitSHA       string   `json:"commitSha,omitempty"`
+	ManifestVersion string   `json:"manifestVersion,omitempty"`
+	Inputs          []string `json:"inputs,omitempty"`
+	Entrypoints     []string `json:"entrypoints,omitempty"`
+}
+
+// Discoverer shallow-clones workflow repos into a cache dir and reads their
+// manifest, refreshing on a TTL and skipping re-clones of unchanged refs.
+type Discoverer struct {
+	cacheDir string
+	ttl      time.Duration
+
+	mu       sync.Mutex
+	results  map[string]DiscoveredEntry
+	lastSHA  map[string]string
+	lastSeen map[string]time.Time
+
+	repoMu sync.Mutex
+	repoLk map[string]*sync.Mutex
+}
+
+// NewDiscoverer creates a Discoverer that caches clones under cacheDir and
+// treats discovered metadata as fresh for ttl.
+func NewDiscoverer(cacheDir string, ttl time.Duration) *Discoverer {
+	return &Discoverer{
+		cacheDir: cacheDir,
+		ttl:      ttl,
+		results:  make(map[string]DiscoveredEntry),
+		lastSHA:  make(map[string]string),
+		lastSeen: make(map[string]time.Time),
+		repoLk:   make(map[string]*sync.Mutex),
+	}
+}
+
+// lockRepo returns the per-repoDir lock, creating it if necessary, so the
+// background refresher and concurrent requests can't clone/fetch the same
+// cache directory at once.
+func (d *Discoverer) lockRepo(repoDir string) *sync.Mutex {
+	d.repoMu.Lock()
+	defer d.repoMu.Unlock()
+	lk, ok := d.repoLk[repoDir]
+	if !ok {
+		lk = &sync.Mutex{}
+		d.repoLk[repoDir] = lk
+	}
+	return lk
+}
+
+// Discover merges manifest-derived metadata over the catalog-declared
+// defaults for each entry. A cached result is returned as-is while it is
+// within the TTL; past the TTL the ref is fetched again and the cached
+// result is reused if the commit SHA hasn't moved.
+func (d *Discoverer) Discover(entries []Entry) []DiscoveredEntry {
+	out := make([]DiscoveredEntry, len(entries))
+	for i, e := range entries {
+		out[i] = d.discoverOne(e)
+	}
+	return out
+}
+
+// DiscoverOne returns discovered metadata for a single entry, honoring the
+// TTL cache like Discover does.
+func (d *Discoverer) DiscoverOne(e Entry) DiscoveredEntry {
+	return d.discoverOne(e)
+}
+
+// Refresh forces re-discovery of a single entry, ignoring the TTL and the
+// unchanged-SHA cache so the manifest is always re-read.
+func (d *Discoverer) Refresh(e Entry) DiscoveredEntry {
+	d.mu.Lock()
+	delete(d.lastSeen, e.ID)
+	delete(d.lastSHA, e.ID)
+	d.mu.Unlock()
+	return d.discoverOne(e)
+}
+
+func (d *Discoverer) discoverOne(e Entry) DiscoveredEntry {
+	d.mu.Lock()
+	if seen, ok := d.lastSeen[e.ID]; ok && time.Since(seen) < d.ttl {
+		cached := d.results[e.ID]
+		d.mu.Unlock()
+		return cached
+	}
+	d.mu.Unlock()
+
+	result := DiscoveredEntry{Entry: e, DiscoveryStatus: DiscoveryOK}
+
+	if e.GitURL == "" {
+		result.DiscoveryStatus = DiscoveryCloneFailed
+		result.DiscoveryError = "entry has no gitUrl configured"
+		d.store(e.ID, result)
+		return result
+	}
+
+	repoDir := filepath.Join(d.cacheDir, sha1Hex(e.GitURL+"@"+e.Branch))
+	repoLk := d.lockRepo(repoDir)
+	repoLk.Lock()
+	sha, err := d.cloneOrFetch(repoDir, e.GitURL, e.Branch)
+	repoLk.Unlock()
+	if err != nil {
+		log.Printf("workflowcatalog: discovery failed for %s: %v", e.ID, err)
+		result.DiscoveryStatus = DiscoveryCloneFailed
+		result.DiscoveryError = err.Error()
+		d.store(e.ID, result)
+		return result
+	}
+	result.CommitSHA = sha
+
+	d.mu.Lock()
+	unchanged := d.lastSHA[e.ID] == sha
+	d.mu.Unlock()
+	if unchanged {
+		d.mu.Lock()
+		cached := d.results[e.ID]
+		d.mu.Unlock()
+		cached.CommitSHA = sha
+		d.store(e.ID, cached)
+		return cached
+	}
+
+	manifest, err := readManifest(repoDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			result.DiscoveryStatus = DiscoveryManifestMissing
+		} else {
+			result.DiscoveryStatus = DiscoveryCloneFailed
+			result.DiscoveryError = err.Error()
+		}
+		d.store(e.ID, result)
+		d.mu.Lock()
+		d.lastSHA[e.ID] = sha
+		d.mu.Unlock()
+		return result
+	}
+
+	if manifest.Name != "" {
+		result.Name = manifest.Name
+	}
+	if manifest.Description != "" {
+		result.Description = manifest.Description
+	}
+	if manifest.Icon != "" {
+		result.Icon = manifest.Icon
+	}
+	result.ManifestVersion = manifest.Version
+	result.Inputs = manifest.Inputs
+	result.Entrypoints = manifest.Entrypoints
+
+	d.store(e.ID, result)
+	d.mu.Lock()
+	d.lastSHA[e.ID] = sha
+	d.mu.Unlock()
+	return result
+}
+
+func (d *Discoverer) store(id string, result DiscoveredEntry) {
+	d.mu.Lock()
+	d.results[id] = result
+	d.lastSeen[id] = time.Now()
+	d.mu.Unlock()
+}
+
+// cloneOrFetch ensures repoDir has a shallow, up-to-date checkout of branch
+// and returns its current commit SHA.
+func (d *Discoverer) cloneOrFetch(repoDir, gitURL, branch string) (string, error) {
+	ref := plumbing.NewBranchReferenceName(branch)
+
+	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+		repo, err := git.PlainClone(repoDir, false, &git.CloneOptions{
+			URL:           gitURL,
+			ReferenceName: ref,
+			Depth:         1,
+			SingleBranch:  true,
+		})
+		if err != nil {
+			// Remove any half-written directory a failed clone left behind;
+			// otherwise os.Stat succeeds on the next attempt, PlainOpen
+			// fails against the incomplete checkout, and discovery is
+			// permanently stuck in clone-failed.
+			_ = os.RemoveAll(repoDir)
+			return "", fmt.Errorf("clone %s@%s: %w", gitURL, branch, err)
+		}
+		head, err := repo.Head()
+		if err != nil {
+			return "", fmt.Errorf("resolve HEAD for %s: %w", gitURL, err)
+		}
+		return head.Hash().String(), nil
+	}
+
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return "", fmt.Errorf("open cached clone %s: %w", repoDir, err)
+	}
+	refSpec := config.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))
+	if err := repo.Fetch(&git.FetchOptions{
+		RefSpecs: []config.RefSpec{refSpec},
+		Depth:    1,
+	}); err != nil && err != git.NoErrAlreadyUpToDate {
+		return "", fmt.Errorf("fetch %s@%s: %w", gitURL, branch, err)
+	}
+	head, err := repo.Reference(ref, true)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s for %s: %w", ref, gitURL, err)
+	}
+	return head.Hash().String(), nil
+}
+
+func readManifest(repoDir string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(repoDir, ManifestPath))
+	if err != nil {
+		return Manifest{}, err
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("parsing %s: %w", ManifestPath, err)
+	}
+	return m, nil
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// StartBackgroundRefresh periodically re-discovers every entry in the
+// catalog so clone/manifest results stay warm even for workflows nobody has
+// requested recently. It returns a stop function; call it to end the
+// background goroutine (e.g. on shutdown).
+func (d *Discoverer) StartBackgroundRefresh(catalog *Catalog, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				for _, e := range catalog.Snapshot() {
+					d.discoverOne(e)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}